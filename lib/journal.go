@@ -0,0 +1,51 @@
+package lib
+
+import (
+	"time"
+
+	emissions "github.com/allora-network/allora-chain/x/emissions/types"
+)
+
+// JournalEventType names one step of the submission lifecycle that a
+// worker or reputer process can pass through on a given topic.
+type JournalEventType string
+
+const (
+	JournalEventWindowCalculated JournalEventType = "window_calculated"
+	JournalEventOpenNonceFetched JournalEventType = "open_nonce_fetched"
+	JournalEventPayloadBuilt     JournalEventType = "payload_built"
+	JournalEventPayloadCommitted JournalEventType = "payload_committed"
+	JournalEventSubmissionFailed JournalEventType = "submission_failed"
+	JournalEventWindowMissed     JournalEventType = "window_missed"
+)
+
+// JournalRole distinguishes which process emitted a JournalEvent.
+type JournalRole string
+
+const (
+	JournalRoleWorker  JournalRole = "worker"
+	JournalRoleReputer JournalRole = "reputer"
+)
+
+// JournalEvent is a single, timestamped step in a topic's submission
+// lifecycle. It carries enough context (block height, nonce, epoch length,
+// error) to reconstruct why a submission did or didn't land in a given
+// epoch, without grepping through free-form log lines.
+type JournalEvent struct {
+	Type        JournalEventType
+	Role        JournalRole
+	TopicId     emissions.TopicId
+	BlockHeight int64
+	Nonce       int64
+	EpochLength int64
+	Err         error
+	Timestamp   time.Time
+}
+
+// Journal receives JournalEvents as a node runs, building a chronological
+// audit trail of every topic's submission attempts. Modeled on the
+// journal-event pattern used by Filecoin's WindowPoStScheduler.
+type Journal interface {
+	RecordEvent(event JournalEvent)
+	Close() error
+}
@@ -0,0 +1,131 @@
+package lib
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+)
+
+// defaultJournalMaxSizeBytes is the size at which the file-backed journal
+// rotates to a fresh file, keeping any single file small enough to tail or
+// ship to log storage without special handling.
+const defaultJournalMaxSizeBytes = 64 * 1024 * 1024 // 64MiB
+
+// journalRecord is the newline-delimited JSON representation of a
+// JournalEvent; errors don't marshal on their own, so they're flattened to
+// a string here.
+type journalRecord struct {
+	Type        JournalEventType `json:"type"`
+	Role        JournalRole      `json:"role"`
+	TopicId     uint64           `json:"topicId"`
+	BlockHeight int64            `json:"blockHeight"`
+	Nonce       int64            `json:"nonce"`
+	EpochLength int64            `json:"epochLength"`
+	Error       string           `json:"error,omitempty"`
+	Timestamp   string           `json:"timestamp"`
+}
+
+// FileJournal is a Journal that appends newline-delimited JSON records to a
+// file, rotating to a new numbered file once the current one exceeds
+// maxSizeBytes.
+type FileJournal struct {
+	mu           sync.Mutex
+	dir          string
+	prefix       string
+	maxSizeBytes int64
+	file         *os.File
+	fileIndex    int
+	currentSize  int64
+}
+
+// NewFileJournal opens (or creates) a journal directory at dir, writing
+// newline-delimited JSON files named "<prefix>-<index>.ndjson".
+func NewFileJournal(dir string, prefix string) (*FileJournal, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create journal directory: %w", err)
+	}
+
+	j := &FileJournal{
+		dir:          dir,
+		prefix:       prefix,
+		maxSizeBytes: defaultJournalMaxSizeBytes,
+	}
+	if err := j.openCurrentFile(); err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+func (j *FileJournal) openCurrentFile() error {
+	path := filepath.Join(j.dir, fmt.Sprintf("%s-%d.ndjson", j.prefix, j.fileIndex))
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open journal file %s: %w", path, err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat journal file %s: %w", path, err)
+	}
+
+	j.file = file
+	j.currentSize = info.Size()
+	return nil
+}
+
+func (j *FileJournal) rotate() error {
+	if err := j.file.Close(); err != nil {
+		log.Warn().Err(err).Msg("Failed to close journal file before rotation")
+	}
+	j.fileIndex++
+	return j.openCurrentFile()
+}
+
+func (j *FileJournal) RecordEvent(event JournalEvent) {
+	record := journalRecord{
+		Type:        event.Type,
+		Role:        event.Role,
+		TopicId:     uint64(event.TopicId),
+		BlockHeight: event.BlockHeight,
+		Nonce:       event.Nonce,
+		EpochLength: event.EpochLength,
+		Timestamp:   event.Timestamp.UTC().Format("2006-01-02T15:04:05.000Z"),
+	}
+	if event.Err != nil {
+		record.Error = event.Err.Error()
+	}
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to marshal journal event")
+		return
+	}
+	line = append(line, '\n')
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.currentSize > j.maxSizeBytes {
+		if err := j.rotate(); err != nil {
+			log.Error().Err(err).Msg("Failed to rotate journal file")
+			return
+		}
+	}
+
+	n, err := j.file.Write(line)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to write journal event")
+		return
+	}
+	j.currentSize += int64(n)
+}
+
+func (j *FileJournal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.file.Close()
+}
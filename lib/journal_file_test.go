@@ -0,0 +1,82 @@
+package lib
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileJournalRecordEventAppendsNdjson(t *testing.T) {
+	dir := t.TempDir()
+	journal, err := NewFileJournal(dir, "journal")
+	if err != nil {
+		t.Fatalf("NewFileJournal() error = %v", err)
+	}
+	defer journal.Close()
+
+	journal.RecordEvent(JournalEvent{
+		Type:        JournalEventPayloadCommitted,
+		Role:        JournalRoleWorker,
+		TopicId:     7,
+		BlockHeight: 100,
+		Nonce:       99,
+		EpochLength: 10,
+		Timestamp:   time.Now(),
+	})
+	journal.RecordEvent(JournalEvent{
+		Type:        JournalEventSubmissionFailed,
+		Role:        JournalRoleReputer,
+		TopicId:     7,
+		BlockHeight: 101,
+		Err:         os.ErrDeadlineExceeded,
+		Timestamp:   time.Now(),
+	})
+
+	path := filepath.Join(dir, "journal-0.ndjson")
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open journal file: %v", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	var lines int
+	for scanner.Scan() {
+		lines++
+	}
+	if lines != 2 {
+		t.Fatalf("got %d journal lines, want 2", lines)
+	}
+}
+
+func TestFileJournalRotatesWhenOverMaxSize(t *testing.T) {
+	dir := t.TempDir()
+	journal, err := NewFileJournal(dir, "journal")
+	if err != nil {
+		t.Fatalf("NewFileJournal() error = %v", err)
+	}
+	defer journal.Close()
+	journal.maxSizeBytes = 1 // every event after the first rotates
+
+	journal.RecordEvent(JournalEvent{Type: JournalEventWindowCalculated, TopicId: 1, Timestamp: time.Now()})
+	journal.RecordEvent(JournalEvent{Type: JournalEventWindowCalculated, TopicId: 1, Timestamp: time.Now()})
+
+	if journal.fileIndex != 1 {
+		t.Fatalf("fileIndex = %d, want 1 after two events over maxSizeBytes", journal.fileIndex)
+	}
+	for _, name := range []string{"journal-0.ndjson", "journal-1.ndjson"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Fatalf("expected rotated file %s to exist: %v", name, err)
+		}
+	}
+}
+
+func TestNoopJournalDiscardsEvents(t *testing.T) {
+	journal := NewNoopJournal()
+	journal.RecordEvent(JournalEvent{Type: JournalEventWindowMissed})
+	if err := journal.Close(); err != nil {
+		t.Fatalf("Close() error = %v, want nil", err)
+	}
+}
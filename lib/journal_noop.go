@@ -0,0 +1,15 @@
+package lib
+
+// NoopJournal discards every event. It satisfies the Journal interface for
+// tests and for operators who don't want a submission-lifecycle audit trail.
+type NoopJournal struct{}
+
+func NewNoopJournal() *NoopJournal {
+	return &NoopJournal{}
+}
+
+func (j *NoopJournal) RecordEvent(event JournalEvent) {}
+
+func (j *NoopJournal) Close() error {
+	return nil
+}
@@ -0,0 +1,65 @@
+package lib
+
+import (
+	"time"
+
+	emissions "github.com/allora-network/allora-chain/x/emissions/types"
+)
+
+// ChainClient is the on-chain read surface NodeConfig needs for topic
+// discovery, on top of the per-topic registration/submission calls it wraps
+// elsewhere. It is implemented by the chain's gRPC client and injected here
+// so this package doesn't depend on the chain SDK's connection setup.
+type ChainClient interface {
+	GetAllTopics() ([]emissions.Topic, error)
+}
+
+// NodeConfig is this node's static worker/reputer assignments plus the
+// runtime-tunable knobs Spawn and the topic-discovery loop read from it.
+type NodeConfig struct {
+	Chain ChainClient
+
+	Worker  []WorkerConfig
+	Reputer []ReputerConfig
+
+	// MetricsListenAddr, if set, is the address Spawn serves /metrics on.
+	MetricsListenAddr string
+
+	// JournalDir, if set, is where Spawn writes the submission-lifecycle
+	// journal; left empty, journal events are discarded.
+	JournalDir string
+
+	// TopicDiscoveryInterval is how often the discovery loop polls the chain
+	// for new topics. Zero means the loop falls back to its own default.
+	TopicDiscoveryInterval time.Duration
+
+	// TopicFilter decides which discovered topics the discovery loop hot-adds
+	// a worker/reputer goroutine for.
+	TopicFilter TopicFilter
+}
+
+// GetAllTopics lists every topic currently registered on chain.
+func (n *NodeConfig) GetAllTopics() ([]emissions.Topic, error) {
+	return n.Chain.GetAllTopics()
+}
+
+// WorkerConfigForTopic returns the configured worker for topicId, if any.
+func (n *NodeConfig) WorkerConfigForTopic(topicId emissions.TopicId) (WorkerConfig, bool) {
+	for _, worker := range n.Worker {
+		if worker.TopicId == topicId {
+			return worker, true
+		}
+	}
+	return WorkerConfig{}, false
+}
+
+// ReputerConfigForTopic is the reputer-side counterpart of
+// WorkerConfigForTopic.
+func (n *NodeConfig) ReputerConfigForTopic(topicId emissions.TopicId) (ReputerConfig, bool) {
+	for _, reputer := range n.Reputer {
+		if reputer.TopicId == topicId {
+			return reputer, true
+		}
+	}
+	return ReputerConfig{}, false
+}
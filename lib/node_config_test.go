@@ -0,0 +1,66 @@
+package lib
+
+import (
+	"errors"
+	"testing"
+
+	emissions "github.com/allora-network/allora-chain/x/emissions/types"
+)
+
+type fakeChainClient struct {
+	topics []emissions.Topic
+	err    error
+}
+
+func (f fakeChainClient) GetAllTopics() ([]emissions.Topic, error) {
+	return f.topics, f.err
+}
+
+func TestNodeConfigGetAllTopicsDelegatesToChainClient(t *testing.T) {
+	want := []emissions.Topic{{Id: 1}, {Id: 2}}
+	n := NodeConfig{Chain: fakeChainClient{topics: want}}
+
+	got, err := n.GetAllTopics()
+	if err != nil {
+		t.Fatalf("GetAllTopics() error = %v, want nil", err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("GetAllTopics() = %v, want %v", got, want)
+	}
+}
+
+func TestNodeConfigGetAllTopicsPropagatesError(t *testing.T) {
+	n := NodeConfig{Chain: fakeChainClient{err: errors.New("rpc unavailable")}}
+	if _, err := n.GetAllTopics(); err == nil {
+		t.Fatalf("GetAllTopics() error = nil, want propagated error")
+	}
+}
+
+func TestNodeConfigWorkerConfigForTopic(t *testing.T) {
+	n := NodeConfig{Worker: []WorkerConfig{{TopicId: 1}, {TopicId: 2}}}
+
+	if _, ok := n.WorkerConfigForTopic(1); !ok {
+		t.Fatalf("WorkerConfigForTopic(1) ok = false, want true")
+	}
+	if _, ok := n.WorkerConfigForTopic(3); ok {
+		t.Fatalf("WorkerConfigForTopic(3) ok = true, want false (not configured)")
+	}
+}
+
+func TestNodeConfigReputerConfigForTopic(t *testing.T) {
+	n := NodeConfig{Reputer: []ReputerConfig{{TopicId: 5}}}
+
+	if _, ok := n.ReputerConfigForTopic(5); !ok {
+		t.Fatalf("ReputerConfigForTopic(5) ok = false, want true")
+	}
+	if _, ok := n.ReputerConfigForTopic(6); ok {
+		t.Fatalf("ReputerConfigForTopic(6) ok = true, want false (not configured)")
+	}
+}
+
+func TestNodeConfigMetricsListenAddrDefaultsToDisabled(t *testing.T) {
+	var n NodeConfig
+	if n.MetricsListenAddr != "" {
+		t.Fatalf("zero-value NodeConfig.MetricsListenAddr = %q, want empty so Spawn skips starting the metrics server", n.MetricsListenAddr)
+	}
+}
@@ -0,0 +1,38 @@
+package lib
+
+import (
+	"regexp"
+
+	emissions "github.com/allora-network/allora-chain/x/emissions/types"
+)
+
+// TopicFilter decides which on-chain topics a discovery poll should hot-add
+// worker/reputer goroutines for. A topic matches if it satisfies every
+// non-empty criterion below; a zero-value TopicFilter matches everything.
+type TopicFilter struct {
+	// AllowedTopicIds, if non-empty, restricts matches to this whitelist.
+	AllowedTopicIds map[emissions.TopicId]bool
+	// MetadataPattern, if set, must match the topic's metadata string.
+	MetadataPattern *regexp.Regexp
+	// LossFunctions, if non-empty, restricts matches to these loss functions.
+	LossFunctions map[string]bool
+	// InferenceModels, if non-empty, restricts matches to these inference models.
+	InferenceModels map[string]bool
+}
+
+// Matches reports whether topic satisfies every criterion configured on f.
+func (f TopicFilter) Matches(topic emissions.Topic) bool {
+	if len(f.AllowedTopicIds) > 0 && !f.AllowedTopicIds[topic.Id] {
+		return false
+	}
+	if f.MetadataPattern != nil && !f.MetadataPattern.MatchString(topic.Metadata) {
+		return false
+	}
+	if len(f.LossFunctions) > 0 && !f.LossFunctions[topic.LossFunction] {
+		return false
+	}
+	if len(f.InferenceModels) > 0 && !f.InferenceModels[topic.InferenceModel] {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,59 @@
+package lib
+
+import (
+	"regexp"
+	"testing"
+
+	emissions "github.com/allora-network/allora-chain/x/emissions/types"
+)
+
+func TestTopicFilterZeroValueMatchesEverything(t *testing.T) {
+	var f TopicFilter
+	topic := emissions.Topic{Id: 1, Metadata: "anything", LossFunction: "mse", InferenceModel: "regression"}
+	if !f.Matches(topic) {
+		t.Fatalf("zero-value TopicFilter.Matches() = false, want true")
+	}
+}
+
+func TestTopicFilterAllowedTopicIds(t *testing.T) {
+	f := TopicFilter{AllowedTopicIds: map[emissions.TopicId]bool{1: true, 2: true}}
+	if !f.Matches(emissions.Topic{Id: 1}) {
+		t.Fatalf("Matches(topic 1) = false, want true (in whitelist)")
+	}
+	if f.Matches(emissions.Topic{Id: 3}) {
+		t.Fatalf("Matches(topic 3) = true, want false (not in whitelist)")
+	}
+}
+
+func TestTopicFilterMetadataPattern(t *testing.T) {
+	f := TopicFilter{MetadataPattern: regexp.MustCompile("^prod-")}
+	if !f.Matches(emissions.Topic{Metadata: "prod-eth-price"}) {
+		t.Fatalf("Matches() = false, want true for metadata matching pattern")
+	}
+	if f.Matches(emissions.Topic{Metadata: "staging-eth-price"}) {
+		t.Fatalf("Matches() = true, want false for metadata not matching pattern")
+	}
+}
+
+func TestTopicFilterLossFunctionAndInferenceModel(t *testing.T) {
+	f := TopicFilter{
+		LossFunctions:   map[string]bool{"mse": true},
+		InferenceModels: map[string]bool{"regression": true},
+	}
+	if !f.Matches(emissions.Topic{LossFunction: "mse", InferenceModel: "regression"}) {
+		t.Fatalf("Matches() = false, want true when both tags match")
+	}
+	if f.Matches(emissions.Topic{LossFunction: "mse", InferenceModel: "classification"}) {
+		t.Fatalf("Matches() = true, want false when inference model doesn't match")
+	}
+}
+
+func TestTopicFilterCombinesCriteriaWithAnd(t *testing.T) {
+	f := TopicFilter{
+		AllowedTopicIds: map[emissions.TopicId]bool{1: true},
+		LossFunctions:   map[string]bool{"mse": true},
+	}
+	if f.Matches(emissions.Topic{Id: 1, LossFunction: "mae"}) {
+		t.Fatalf("Matches() = true, want false when topic id matches but loss function doesn't")
+	}
+}
@@ -0,0 +1,14 @@
+package lib
+
+import emissions "github.com/allora-network/allora-chain/x/emissions/types"
+
+// WorkerConfig is one worker process's static configuration: which topic it
+// submits inferences for.
+type WorkerConfig struct {
+	TopicId emissions.TopicId
+}
+
+// ReputerConfig is the reputer-side counterpart of WorkerConfig.
+type ReputerConfig struct {
+	TopicId emissions.TopicId
+}
@@ -0,0 +1,129 @@
+// Package metrics exposes the node's per-topic submission health as
+// Prometheus metrics, so operators running many topics can alert on a
+// stalled worker or reputer instead of tailing logs.
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/rs/zerolog/log"
+)
+
+// Result labels used across the submission counters below.
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+)
+
+// Role labels used across the window-miss and commit-latency metrics below.
+const (
+	RoleWorker  = "worker"
+	RoleReputer = "reputer"
+)
+
+var (
+	WorkerSubmissionsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "allora_worker_submissions_total",
+			Help: "Total number of worker inference submissions attempted, by topic and result.",
+		},
+		[]string{"topic", "result"},
+	)
+
+	ReputerSubmissionsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "allora_reputer_submissions_total",
+			Help: "Total number of reputer submissions attempted, by topic and result.",
+		},
+		[]string{"topic", "result"},
+	)
+
+	WindowMissesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "allora_window_misses_total",
+			Help: "Total number of polls at which the current block was outside a topic's anticipated submission window.",
+		},
+		[]string{"topic", "role"},
+	)
+
+	OpenNonceFetchErrorsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "allora_open_nonce_fetch_errors_total",
+			Help: "Total number of errors fetching the latest open nonce for a topic.",
+		},
+		[]string{"topic"},
+	)
+
+	CommitLatencySeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "allora_commit_latency_seconds",
+			Help:    "Time spent building and broadcasting a commit payload, by topic and role.",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"topic", "role"},
+	)
+
+	CurrentBlockHeight = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "allora_current_block_height",
+			Help: "Most recently observed chain block height.",
+		},
+	)
+
+	AnticipatedWindowOpenBlock = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "allora_anticipated_window_open_block",
+			Help: "Block height at which a topic's anticipated submission window is expected to open, by topic and role.",
+		},
+		[]string{"topic", "role"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(
+		WorkerSubmissionsTotal,
+		ReputerSubmissionsTotal,
+		WindowMissesTotal,
+		OpenNonceFetchErrorsTotal,
+		CommitLatencySeconds,
+		CurrentBlockHeight,
+		AnticipatedWindowOpenBlock,
+	)
+}
+
+// Serve starts the /metrics HTTP endpoint on addr in the background and
+// returns the underlying server so the caller can shut it down. It does not
+// block; a failure to bind is logged rather than returned, since a metrics
+// outage shouldn't take the node down with it.
+func Serve(addr string) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+
+	server := &http.Server{
+		Addr:    addr,
+		Handler: mux,
+	}
+
+	go func() {
+		log.Info().Str("addr", addr).Msg("Serving Prometheus metrics")
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Error().Err(err).Str("addr", addr).Msg("Metrics server stopped unexpectedly")
+		}
+	}()
+
+	return server
+}
+
+// Shutdown gracefully stops the metrics server, giving in-flight scrapes up
+// to 5 seconds to complete.
+func Shutdown(server *http.Server) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		log.Warn().Err(err).Msg("Error shutting down metrics server")
+	}
+}
@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestCountersAndGaugesRecordByLabel(t *testing.T) {
+	WorkerSubmissionsTotal.Reset()
+	ReputerSubmissionsTotal.Reset()
+	WindowMissesTotal.Reset()
+	OpenNonceFetchErrorsTotal.Reset()
+
+	WorkerSubmissionsTotal.WithLabelValues("1", ResultSuccess).Inc()
+	WorkerSubmissionsTotal.WithLabelValues("1", ResultFailure).Inc()
+	ReputerSubmissionsTotal.WithLabelValues("1", ResultSuccess).Inc()
+	WindowMissesTotal.WithLabelValues("1", RoleWorker).Inc()
+	OpenNonceFetchErrorsTotal.WithLabelValues("1").Inc()
+	CommitLatencySeconds.WithLabelValues("1", RoleWorker).Observe(0.5)
+	CurrentBlockHeight.Set(42)
+	AnticipatedWindowOpenBlock.WithLabelValues("1", RoleWorker).Set(100)
+
+	if got := testutil.ToFloat64(WorkerSubmissionsTotal.WithLabelValues("1", ResultSuccess)); got != 1 {
+		t.Fatalf("WorkerSubmissionsTotal{success} = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(WorkerSubmissionsTotal.WithLabelValues("1", ResultFailure)); got != 1 {
+		t.Fatalf("WorkerSubmissionsTotal{failure} = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(ReputerSubmissionsTotal.WithLabelValues("1", ResultSuccess)); got != 1 {
+		t.Fatalf("ReputerSubmissionsTotal{success} = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(CurrentBlockHeight); got != 42 {
+		t.Fatalf("CurrentBlockHeight = %v, want 42", got)
+	}
+	if got := testutil.ToFloat64(AnticipatedWindowOpenBlock.WithLabelValues("1", RoleWorker)); got != 100 {
+		t.Fatalf("AnticipatedWindowOpenBlock = %v, want 100", got)
+	}
+}
+
+func TestServeExposesMetricsEndpoint(t *testing.T) {
+	addr := "127.0.0.1:19091"
+	server := Serve(addr)
+	defer Shutdown(server)
+
+	url := fmt.Sprintf("http://%s/metrics", addr)
+	var resp *http.Response
+	var err error
+	for i := 0; i < 50; i++ {
+		resp, err = http.Get(url)
+		if err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatalf("GET %s failed after retrying: %v", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET %s status = %d, want 200", url, resp.StatusCode)
+	}
+}
@@ -0,0 +1,229 @@
+package usecase
+
+import (
+	"allora_offchain_node/lib"
+	"context"
+	"sync"
+	"time"
+
+	emissions "github.com/allora-network/allora-chain/x/emissions/types"
+	"github.com/rs/zerolog/log"
+)
+
+// defaultTopicDiscoveryInterval is how often Spawn polls the chain for newly
+// created topics when the operator hasn't configured a positive interval.
+const defaultTopicDiscoveryInterval = 1 * time.Minute
+
+// TopicRegistry is the live, mutex-protected set of topics that currently
+// have a running worker or reputer goroutine. It replaces the one-shot
+// alreadyStartedFor* dedupe maps so that topics can be added and removed
+// while Spawn is running, rather than only once at startup.
+type TopicRegistry struct {
+	mu       sync.Mutex
+	workers  map[emissions.TopicId]context.CancelFunc
+	reputers map[emissions.TopicId]context.CancelFunc
+}
+
+func NewTopicRegistry() *TopicRegistry {
+	return &TopicRegistry{
+		workers:  make(map[emissions.TopicId]context.CancelFunc),
+		reputers: make(map[emissions.TopicId]context.CancelFunc),
+	}
+}
+
+// addWorker registers topicId as started, with cancel as the function that
+// tears it down, and returns true; it returns false and registers nothing if
+// a worker for this topic is already running.
+func (r *TopicRegistry) addWorker(topicId emissions.TopicId, cancel context.CancelFunc) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.workers[topicId]; ok {
+		return false
+	}
+	r.workers[topicId] = cancel
+	return true
+}
+
+// addReputer is the reputer-side counterpart of addWorker.
+func (r *TopicRegistry) addReputer(topicId emissions.TopicId, cancel context.CancelFunc) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.reputers[topicId]; ok {
+		return false
+	}
+	r.reputers[topicId] = cancel
+	return true
+}
+
+// removeWorker cancels and forgets a topic's worker goroutine, if one is
+// running. It is a no-op if the topic was never started.
+func (r *TopicRegistry) removeWorker(topicId emissions.TopicId) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if cancel, ok := r.workers[topicId]; ok {
+		cancel()
+		delete(r.workers, topicId)
+	}
+}
+
+// removeReputer is the reputer-side counterpart of removeWorker.
+func (r *TopicRegistry) removeReputer(topicId emissions.TopicId) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if cancel, ok := r.reputers[topicId]; ok {
+		cancel()
+		delete(r.reputers, topicId)
+	}
+}
+
+func (r *TopicRegistry) hasWorker(topicId emissions.TopicId) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.workers[topicId]
+	return ok
+}
+
+func (r *TopicRegistry) hasReputer(topicId emissions.TopicId) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.reputers[topicId]
+	return ok
+}
+
+// workerTopicIds returns the topic ids that currently have a running worker
+// goroutine.
+func (r *TopicRegistry) workerTopicIds() []emissions.TopicId {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ids := make([]emissions.TopicId, 0, len(r.workers))
+	for topicId := range r.workers {
+		ids = append(ids, topicId)
+	}
+	return ids
+}
+
+// reputerTopicIds returns the topic ids that currently have a running
+// reputer goroutine.
+func (r *TopicRegistry) reputerTopicIds() []emissions.TopicId {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ids := make([]emissions.TopicId, 0, len(r.reputers))
+	for topicId := range r.reputers {
+		ids = append(ids, topicId)
+	}
+	return ids
+}
+
+// startWorkerForTopic spawns a supervised worker goroutine for worker's
+// topic, deriving a cancelable child context from parentCtx so the topic can
+// be stopped independently of the rest of the node. It is a no-op if the
+// topic already has a running worker.
+func (suite *UseCaseSuite) startWorkerForTopic(parentCtx context.Context, registry *TopicRegistry, supervisors *SupervisorRegistry, wg *sync.WaitGroup, worker lib.WorkerConfig) {
+	topicCtx, cancel := context.WithCancel(parentCtx)
+	if !registry.addWorker(worker.TopicId, cancel) {
+		cancel()
+		log.Debug().Uint64("topicId", worker.TopicId).Msg("Worker already started for topicId")
+		return
+	}
+
+	state := supervisors.registerWorker(worker.TopicId)
+	wg.Add(1)
+	go func(worker lib.WorkerConfig) {
+		defer wg.Done()
+		suite.superviseWorkerProcess(topicCtx, worker, state)
+	}(worker)
+}
+
+// startReputerForTopic is the reputer-side counterpart of
+// startWorkerForTopic.
+func (suite *UseCaseSuite) startReputerForTopic(parentCtx context.Context, registry *TopicRegistry, supervisors *SupervisorRegistry, wg *sync.WaitGroup, reputer lib.ReputerConfig) {
+	topicCtx, cancel := context.WithCancel(parentCtx)
+	if !registry.addReputer(reputer.TopicId, cancel) {
+		cancel()
+		log.Debug().Uint64("topicId", reputer.TopicId).Msg("Reputer already started for topicId")
+		return
+	}
+
+	state := supervisors.registerReputer(reputer.TopicId)
+	wg.Add(1)
+	go func(reputer lib.ReputerConfig) {
+		defer wg.Done()
+		suite.superviseReputerProcess(topicCtx, reputer, state)
+	}(reputer)
+}
+
+// runTopicDiscovery periodically polls the chain for topics matching the
+// operator's configured filter and hot-adds worker/reputer goroutines for
+// any that aren't already running. It also tears down topics that no longer
+// match the filter, e.g. because the operator edited their whitelist.
+// It returns when ctx is cancelled.
+func (suite *UseCaseSuite) runTopicDiscovery(ctx context.Context, registry *TopicRegistry, supervisors *SupervisorRegistry, wg *sync.WaitGroup) {
+	interval := suite.Node.TopicDiscoveryInterval
+	if interval <= 0 {
+		interval = defaultTopicDiscoveryInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			suite.discoverTopicsOnce(ctx, registry, supervisors, wg)
+		}
+	}
+}
+
+// discoverTopicsOnce lists every on-chain topic, hot-adds a worker/reputer
+// goroutine for each newly-matching one, and hot-removes any currently
+// running goroutine whose topic no longer matches the filter or is no longer
+// listed by GetAllTopics (e.g. the operator narrowed the whitelist). A topic's
+// on-chain active status is not checked: a listed topic that still matches
+// keeps running.
+func (suite *UseCaseSuite) discoverTopicsOnce(ctx context.Context, registry *TopicRegistry, supervisors *SupervisorRegistry, wg *sync.WaitGroup) {
+	topics, err := suite.Node.GetAllTopics()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list topics during discovery poll")
+		return
+	}
+
+	matchedWorkers := make(map[emissions.TopicId]bool)
+	matchedReputers := make(map[emissions.TopicId]bool)
+
+	for _, topic := range topics {
+		if !suite.Node.TopicFilter.Matches(topic) {
+			continue
+		}
+
+		if worker, ok := suite.Node.WorkerConfigForTopic(topic.Id); ok {
+			matchedWorkers[topic.Id] = true
+			if !registry.hasWorker(topic.Id) {
+				log.Info().Uint64("topicId", topic.Id).Msg("Discovered new topic matching worker filter, starting worker")
+				suite.startWorkerForTopic(ctx, registry, supervisors, wg, worker)
+			}
+		}
+
+		if reputer, ok := suite.Node.ReputerConfigForTopic(topic.Id); ok {
+			matchedReputers[topic.Id] = true
+			if !registry.hasReputer(topic.Id) {
+				log.Info().Uint64("topicId", topic.Id).Msg("Discovered new topic matching reputer filter, starting reputer")
+				suite.startReputerForTopic(ctx, registry, supervisors, wg, reputer)
+			}
+		}
+	}
+
+	for _, topicId := range registry.workerTopicIds() {
+		if !matchedWorkers[topicId] {
+			log.Info().Uint64("topicId", topicId).Msg("Topic no longer matches worker filter, stopping worker")
+			registry.removeWorker(topicId)
+		}
+	}
+	for _, topicId := range registry.reputerTopicIds() {
+		if !matchedReputers[topicId] {
+			log.Info().Uint64("topicId", topicId).Msg("Topic no longer matches reputer filter, stopping reputer")
+			registry.removeReputer(topicId)
+		}
+	}
+}
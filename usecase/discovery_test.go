@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+)
+
+func TestTopicRegistryAddWorkerDedupes(t *testing.T) {
+	registry := NewTopicRegistry()
+	_, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if !registry.addWorker(1, cancel) {
+		t.Fatalf("addWorker(1) = false on first call, want true")
+	}
+	if registry.addWorker(1, cancel) {
+		t.Fatalf("addWorker(1) = true on second call, want false (already running)")
+	}
+	if !registry.hasWorker(1) {
+		t.Fatalf("hasWorker(1) = false, want true")
+	}
+}
+
+func TestTopicRegistryRemoveWorkerCancelsAndForgets(t *testing.T) {
+	registry := NewTopicRegistry()
+	cancelled := false
+	cancel := func() { cancelled = true }
+
+	registry.addWorker(1, cancel)
+	registry.removeWorker(1)
+
+	if !cancelled {
+		t.Fatalf("removeWorker did not call the topic's cancel func")
+	}
+	if registry.hasWorker(1) {
+		t.Fatalf("hasWorker(1) = true after removeWorker, want false")
+	}
+
+	// removing again is a no-op, not a second cancel or a panic
+	registry.removeWorker(1)
+}
+
+func TestTopicRegistryWorkerAndReputerAreIndependent(t *testing.T) {
+	registry := NewTopicRegistry()
+	registry.addWorker(1, func() {})
+
+	if registry.hasReputer(1) {
+		t.Fatalf("hasReputer(1) = true after only addWorker, want false")
+	}
+	if ids := registry.reputerTopicIds(); len(ids) != 0 {
+		t.Fatalf("reputerTopicIds() = %v, want empty", ids)
+	}
+	if ids := registry.workerTopicIds(); len(ids) != 1 || ids[0] != 1 {
+		t.Fatalf("workerTopicIds() = %v, want [1]", ids)
+	}
+}
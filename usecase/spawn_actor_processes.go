@@ -2,79 +2,157 @@ package usecase
 
 import (
 	"allora_offchain_node/lib"
+	"allora_offchain_node/metrics"
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
 	"sync"
+	"syscall"
+	"time"
 
 	emissions "github.com/allora-network/allora-chain/x/emissions/types"
 	"github.com/rs/zerolog/log"
 )
 
 func (suite *UseCaseSuite) Spawn() {
-	var wg sync.WaitGroup
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
-	// Run worker process per topic
-	alreadyStartedWorkerForTopic := make(map[emissions.TopicId]bool)
-	for _, worker := range suite.Node.Worker {
-		if _, ok := alreadyStartedWorkerForTopic[worker.TopicId]; ok {
-			log.Debug().Uint64("topicId", worker.TopicId).Msg("Worker already started for topicId")
-			continue
-		}
-		alreadyStartedWorkerForTopic[worker.TopicId] = true
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM, syscall.SIGQUIT)
+	go func() {
+		sig := <-sigCh
+		log.Info().Str("signal", sig.String()).Msg("Received shutdown signal, winding down in-flight submissions")
+		cancel()
+	}()
 
-		wg.Add(1)
-		go func(worker lib.WorkerConfig) {
-			defer wg.Done()
-			suite.runWorkerProcess(worker)
-		}(worker)
+	// Start the metrics server before any topic goroutines, so scrapers can
+	// see the process immediately and don't race its first submission
+	if suite.Node.MetricsListenAddr != "" {
+		metricsServer := metrics.Serve(suite.Node.MetricsListenAddr)
+		defer metrics.Shutdown(metricsServer)
 	}
 
-	// Run reputer process per topic
-	alreadyStartedReputerForTopic := make(map[emissions.TopicId]bool)
-	for _, reputer := range suite.Node.Reputer {
-		if _, ok := alreadyStartedReputerForTopic[reputer.TopicId]; ok {
-			log.Debug().Uint64("topicId", reputer.TopicId).Msg("Reputer already started for topicId")
-			continue
+	// Every worker/reputer loop below records its lifecycle to suite.Journal
+	// unconditionally, so it must be set before any goroutine starts. Default
+	// to discarding events; an operator who wants the on-disk audit trail
+	// opts in with a journal directory.
+	if suite.Node.JournalDir != "" {
+		journal, err := lib.NewFileJournal(suite.Node.JournalDir, "journal")
+		if err != nil {
+			log.Error().Err(err).Str("dir", suite.Node.JournalDir).Msg("Failed to open journal directory, falling back to no-op journal")
+			suite.Journal = lib.NewNoopJournal()
+		} else {
+			suite.Journal = journal
+			defer journal.Close()
 		}
-		alreadyStartedReputerForTopic[reputer.TopicId] = true
+	} else {
+		suite.Journal = lib.NewNoopJournal()
+	}
 
-		wg.Add(1)
-		go func(reputer lib.ReputerConfig) {
-			defer wg.Done()
-			suite.runReputerProcess(reputer)
-		}(reputer)
+	var wg sync.WaitGroup
+
+	supervisors := NewSupervisorRegistry()
+	suite.Supervisors = supervisors
+
+	// registry is the live set of topics with a running worker/reputer
+	// goroutine; it lets runTopicDiscovery hot-add and hot-remove topics
+	// after this initial fan-out, instead of only dispatching once at boot
+	registry := NewTopicRegistry()
+
+	// Run worker process per topic, under a supervisor that restarts it with
+	// backoff if it terminates abnormally
+	for _, worker := range suite.Node.Worker {
+		suite.startWorkerForTopic(ctx, registry, supervisors, &wg, worker)
 	}
 
-	// Wait for all goroutines to finish
+	// Run reputer process per topic, under a supervisor that restarts it with
+	// backoff if it terminates abnormally
+	for _, reputer := range suite.Node.Reputer {
+		suite.startReputerForTopic(ctx, registry, supervisors, &wg, reputer)
+	}
+
+	// Periodically poll the chain for newly created topics matching the
+	// operator's discovery filter, and hot-add/hot-remove goroutines for them
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		suite.runTopicDiscovery(ctx, registry, supervisors, &wg)
+	}()
+
+	// Wait for all goroutines to finish, either by running forever or by
+	// draining in response to the cancelled context
 	wg.Wait()
+	log.Info().Msg("All worker and reputer processes have drained, shutting down")
+}
+
+// topicLabel formats a topic id for use as a Prometheus label value.
+func topicLabel(topicId emissions.TopicId) string {
+	return strconv.FormatUint(topicId, 10)
+}
+
+// waitCancelable runs wait to completion in the background and returns as
+// soon as either wait returns or ctx is cancelled, whichever comes first.
+// The anticipated-window Wait* helpers block for their full duration with no
+// cancellation path of their own, so this lets a shutdown signal interrupt
+// them promptly instead of stalling the drain for up to a full epoch.
+func waitCancelable(ctx context.Context, wait func()) {
+	done := make(chan struct{})
+	go func() {
+		wait()
+		close(done)
+	}()
+	select {
+	case <-ctx.Done():
+	case <-done:
+	}
 }
 
-func (suite *UseCaseSuite) runWorkerProcess(worker lib.WorkerConfig) {
+func (suite *UseCaseSuite) runWorkerProcess(ctx context.Context, worker lib.WorkerConfig, state *TopicSupervisorState) error {
 	log.Info().Uint64("topicId", worker.TopicId).Msg("Running worker process for topic")
 
 	topic, err := suite.Node.GetTopicById(worker.TopicId)
 	if err != nil {
 		log.Error().Err(err).Uint64("topicId", worker.TopicId).Msg("Failed to get topic")
-		return
+		return err
 	}
 
 	registered := suite.Node.RegisterWorkerIdempotently(worker)
 	if !registered {
 		log.Error().Err(err).Uint64("topicId", worker.TopicId).Msg("Failed to register worker for topic")
-		return
+		return fmt.Errorf("failed to register worker for topic %d", worker.TopicId)
 	}
 
 	mustRecalcWindow := true
 	window := AnticipatedWindow{}
 	for {
+		if ctx.Err() != nil {
+			log.Debug().Uint64("topicId", worker.TopicId).Msg("Context cancelled, stopping worker process for topic")
+			return nil
+		}
+
 		currentBlock, err := suite.Node.GetCurrentChainBlockHeight()
 		if err != nil {
 			log.Error().Err(err).Uint64("topicId", worker.TopicId).Msg("Error getting chain block height for worker job on topic")
-			return
+			return err
 		}
+		metrics.CurrentBlockHeight.Set(float64(currentBlock))
 
 		if mustRecalcWindow {
 
 			window = window.CalcWorkerSoonestAnticipatedWindow(suite, topic, currentBlock)
 			log.Debug().Msgf("Worker anticipated window for topic %d open nonce. Open: %f Close $f %v", worker.TopicId, window.SoonestTimeForOpenNonceCheck, window.SoonestTimeForEndOfWorkerNonceSubmission)
+			metrics.AnticipatedWindowOpenBlock.WithLabelValues(topicLabel(worker.TopicId), metrics.RoleWorker).Set(window.SoonestTimeForOpenNonceCheck)
+			suite.Journal.RecordEvent(lib.JournalEvent{
+				Type:        lib.JournalEventWindowCalculated,
+				Role:        lib.JournalRoleWorker,
+				TopicId:     worker.TopicId,
+				BlockHeight: currentBlock,
+				EpochLength: topic.EpochLength,
+				Timestamp:   time.Now(),
+			})
 			mustRecalcWindow = false
 		}
 
@@ -84,43 +162,94 @@ func (suite *UseCaseSuite) runWorkerProcess(worker lib.WorkerConfig) {
 			latestOpenWorkerNonce, err := suite.Node.GetLatestOpenWorkerNonceByTopicId(worker.TopicId)
 			if latestOpenWorkerNonce.BlockHeight == 0 || err != nil {
 				log.Error().Err(err).Uint64("topicId", worker.TopicId).Msg("Error getting latest open worker nonce on topic")
+				metrics.OpenNonceFetchErrorsTotal.WithLabelValues(topicLabel(worker.TopicId)).Inc()
 				attemptCommit = false
 			}
 			log.Info().Int64("latestOpenWorkerNonce", latestOpenWorkerNonce.BlockHeight).Uint64("topicId", worker.TopicId).Msg("Got latest open worker nonce")
+			suite.Journal.RecordEvent(lib.JournalEvent{
+				Type:        lib.JournalEventOpenNonceFetched,
+				Role:        lib.JournalRoleWorker,
+				TopicId:     worker.TopicId,
+				BlockHeight: currentBlock,
+				Nonce:       latestOpenWorkerNonce.BlockHeight,
+				Err:         err,
+				Timestamp:   time.Now(),
+			})
 
 			if attemptCommit {
+				suite.Journal.RecordEvent(lib.JournalEvent{
+					Type:        lib.JournalEventPayloadBuilt,
+					Role:        lib.JournalRoleWorker,
+					TopicId:     worker.TopicId,
+					BlockHeight: currentBlock,
+					Nonce:       latestOpenWorkerNonce.BlockHeight,
+					Timestamp:   time.Now(),
+				})
+				// Once a commit has started it runs to completion even if the
+				// context is cancelled mid-flight; we only stop picking up new work
+				commitStartedAt := time.Now()
 				success, err := suite.BuildCommitWorkerPayload(worker, latestOpenWorkerNonce)
+				metrics.CommitLatencySeconds.WithLabelValues(topicLabel(worker.TopicId), metrics.RoleWorker).Observe(time.Since(commitStartedAt).Seconds())
 				if err != nil {
 					log.Error().Err(err).Uint64("topicId", worker.TopicId).Msg("Error building and committing worker payload for topic")
+					metrics.WorkerSubmissionsTotal.WithLabelValues(topicLabel(worker.TopicId), metrics.ResultFailure).Inc()
+					suite.Journal.RecordEvent(lib.JournalEvent{
+						Type:        lib.JournalEventSubmissionFailed,
+						Role:        lib.JournalRoleWorker,
+						TopicId:     worker.TopicId,
+						BlockHeight: currentBlock,
+						Nonce:       latestOpenWorkerNonce.BlockHeight,
+						Err:         err,
+						Timestamp:   time.Now(),
+					})
 				}
 				if success {
+					metrics.WorkerSubmissionsTotal.WithLabelValues(topicLabel(worker.TopicId), metrics.ResultSuccess).Inc()
+					suite.Journal.RecordEvent(lib.JournalEvent{
+						Type:        lib.JournalEventPayloadCommitted,
+						Role:        lib.JournalRoleWorker,
+						TopicId:     worker.TopicId,
+						BlockHeight: currentBlock,
+						Nonce:       latestOpenWorkerNonce.BlockHeight,
+						Timestamp:   time.Now(),
+					})
+					state.recordSuccess()
 					mustRecalcWindow = true
-					window.WaitForNextAnticipatedWindowToStart(currentBlock, topic.EpochLength)
+					waitCancelable(ctx, func() { window.WaitForNextAnticipatedWindowToStart(currentBlock, topic.EpochLength) })
 					continue
 				}
 			}
 
-			suite.WaitWithinAnticipatedWindow()
+			waitCancelable(ctx, suite.WaitWithinAnticipatedWindow)
 		} else {
 			log.Debug().Msgf("Block %d is not within window. Open: %f Close: %f", currentBlock, window.SoonestTimeForOpenNonceCheck, window.SoonestTimeForEndOfWorkerNonceSubmission)
-			window.WaitForNextAnticipatedWindowToStart(currentBlock, topic.EpochLength)
+			metrics.WindowMissesTotal.WithLabelValues(topicLabel(worker.TopicId), metrics.RoleWorker).Inc()
+			suite.Journal.RecordEvent(lib.JournalEvent{
+				Type:        lib.JournalEventWindowMissed,
+				Role:        lib.JournalRoleWorker,
+				TopicId:     worker.TopicId,
+				BlockHeight: currentBlock,
+				EpochLength: topic.EpochLength,
+				Timestamp:   time.Now(),
+			})
+			waitCancelable(ctx, func() { window.WaitForNextAnticipatedWindowToStart(currentBlock, topic.EpochLength) })
 		}
 	}
 }
 
-func (suite *UseCaseSuite) runReputerProcess(reputer lib.ReputerConfig) {
+func (suite *UseCaseSuite) runReputerProcess(ctx context.Context, reputer lib.ReputerConfig, state *TopicSupervisorState) error {
 	log.Debug().Uint64("topicId", reputer.TopicId).Msg("Running reputer process for topic")
 
 	topic, err := suite.Node.GetTopicById(reputer.TopicId)
 	if err != nil {
 		log.Error().Err(err).Uint64("topicId", reputer.TopicId).Msg("Failed to get topic")
-		return
+		return err
 	}
 
 	registeredAndStaked := suite.Node.RegisterAndStakeReputerIdempotently(reputer)
 	if !registeredAndStaked {
 		log.Error().Err(err).Uint64("topicId", reputer.TopicId).Msg("Failed to register or sufficiently stake reputer for topic")
-		return
+		return fmt.Errorf("failed to register or stake reputer for topic %d", reputer.TopicId)
 	}
 
 	var latestOpenReputerNonce *emissions.Nonce
@@ -129,53 +258,136 @@ func (suite *UseCaseSuite) runReputerProcess(reputer lib.ReputerConfig) {
 	mustRecalcReputerWindow := true
 	window := AnticipatedWindow{}
 	for {
+		if ctx.Err() != nil {
+			log.Debug().Uint64("topicId", reputer.TopicId).Msg("Context cancelled, stopping reputer process for topic")
+			return nil
+		}
+
 		currentBlock, err := suite.Node.GetCurrentChainBlockHeight()
 		if err != nil {
 			log.Error().Err(err).Uint64("topicId", reputer.TopicId).Msg("Error getting chain block height for reputer job on topic")
-			return
+			return err
 		}
+		metrics.CurrentBlockHeight.Set(float64(currentBlock))
 
 		// Try to get the open nonce for the reputer
 		if mustRecalcOpenNonceWindow {
 			window = window.CalcWorkerSoonestAnticipatedWindow(suite, topic, currentBlock)
 			log.Debug().Msgf("Reputer anticipated window for open nonce for topic %d: %v", reputer.TopicId, window)
+			metrics.AnticipatedWindowOpenBlock.WithLabelValues(topicLabel(reputer.TopicId), metrics.RoleReputer).Set(window.SoonestTimeForOpenNonceCheck)
+			suite.Journal.RecordEvent(lib.JournalEvent{
+				Type:        lib.JournalEventWindowCalculated,
+				Role:        lib.JournalRoleReputer,
+				TopicId:     reputer.TopicId,
+				BlockHeight: currentBlock,
+				EpochLength: topic.EpochLength,
+				Timestamp:   time.Now(),
+			})
 			mustRecalcOpenNonceWindow = false
 		}
 
 		if mustGetOpenNonce && window.BlockIsWithinWindow(currentBlock) {
 			latestOpenReputerNonce, err = suite.Node.GetLatestOpenWorkerNonceByTopicId(reputer.TopicId)
-			println(latestOpenReputerNonce.BlockHeight)
 			if latestOpenReputerNonce.BlockHeight == 0 || err != nil {
 				log.Error().Err(err).Uint64("topicId", reputer.TopicId).Msg("Error getting latest open reputer nonce on topic")
+				metrics.OpenNonceFetchErrorsTotal.WithLabelValues(topicLabel(reputer.TopicId)).Inc()
+				suite.Journal.RecordEvent(lib.JournalEvent{
+					Type:        lib.JournalEventOpenNonceFetched,
+					Role:        lib.JournalRoleReputer,
+					TopicId:     reputer.TopicId,
+					BlockHeight: currentBlock,
+					Err:         err,
+					Timestamp:   time.Now(),
+				})
 				mustGetOpenNonce = true
 				continue
 			}
+			suite.Journal.RecordEvent(lib.JournalEvent{
+				Type:        lib.JournalEventOpenNonceFetched,
+				Role:        lib.JournalRoleReputer,
+				TopicId:     reputer.TopicId,
+				BlockHeight: currentBlock,
+				Nonce:       latestOpenReputerNonce.BlockHeight,
+				Timestamp:   time.Now(),
+			})
 			mustGetOpenNonce = false
 		}
 
 		if mustRecalcReputerWindow {
 			window = *window.CalcReputerSoonestAnticipatedWindow(topic, latestOpenReputerNonce.BlockHeight)
 			log.Debug().Msgf("Reputer anticipated window for submission for topic %d: %v", reputer.TopicId, window)
+			suite.Journal.RecordEvent(lib.JournalEvent{
+				Type:        lib.JournalEventWindowCalculated,
+				Role:        lib.JournalRoleReputer,
+				TopicId:     reputer.TopicId,
+				BlockHeight: currentBlock,
+				Nonce:       latestOpenReputerNonce.BlockHeight,
+				EpochLength: topic.EpochLength,
+				Timestamp:   time.Now(),
+			})
 			mustRecalcReputerWindow = false
 		}
 
 		if window.BlockIsWithinReputerWindow(currentBlock) {
+			suite.Journal.RecordEvent(lib.JournalEvent{
+				Type:        lib.JournalEventPayloadBuilt,
+				Role:        lib.JournalRoleReputer,
+				TopicId:     reputer.TopicId,
+				BlockHeight: currentBlock,
+				Nonce:       latestOpenReputerNonce.BlockHeight,
+				Timestamp:   time.Now(),
+			})
+			// Once a commit has started it runs to completion even if the
+			// context is cancelled mid-flight; we only stop picking up new work
+			commitStartedAt := time.Now()
 			success, err := suite.BuildCommitReputerPayload(reputer, latestOpenReputerNonce.BlockHeight)
+			metrics.CommitLatencySeconds.WithLabelValues(topicLabel(reputer.TopicId), metrics.RoleReputer).Observe(time.Since(commitStartedAt).Seconds())
 			if err != nil {
 				log.Error().Err(err).Uint64("topicId", reputer.TopicId).Msg("Error building and committing worker payload for topic")
+				metrics.ReputerSubmissionsTotal.WithLabelValues(topicLabel(reputer.TopicId), metrics.ResultFailure).Inc()
+				suite.Journal.RecordEvent(lib.JournalEvent{
+					Type:        lib.JournalEventSubmissionFailed,
+					Role:        lib.JournalRoleReputer,
+					TopicId:     reputer.TopicId,
+					BlockHeight: currentBlock,
+					Nonce:       latestOpenReputerNonce.BlockHeight,
+					Err:         err,
+					Timestamp:   time.Now(),
+				})
 			}
 			if success {
+				metrics.ReputerSubmissionsTotal.WithLabelValues(topicLabel(reputer.TopicId), metrics.ResultSuccess).Inc()
+				suite.Journal.RecordEvent(lib.JournalEvent{
+					Type:        lib.JournalEventPayloadCommitted,
+					Role:        lib.JournalRoleReputer,
+					TopicId:     reputer.TopicId,
+					BlockHeight: currentBlock,
+					Nonce:       latestOpenReputerNonce.BlockHeight,
+					Timestamp:   time.Now(),
+				})
+				state.recordSuccess()
 				mustRecalcOpenNonceWindow = true
 				mustGetOpenNonce = true
 				mustRecalcReputerWindow = true
-				window.WaitForNextAnticipatedWindowToStart(currentBlock, topic.EpochLength)
+				waitCancelable(ctx, func() { window.WaitForNextAnticipatedWindowToStart(currentBlock, topic.EpochLength) })
 				continue
 			} else {
-				suite.WaitWithinAnticipatedWindow()
+				waitCancelable(ctx, suite.WaitWithinAnticipatedWindow)
 			}
 
 		} else {
-			window.WaitForNextReputerAnticipatedWindowToStart(topic, latestOpenReputerNonce.BlockHeight, currentBlock)
+			metrics.WindowMissesTotal.WithLabelValues(topicLabel(reputer.TopicId), metrics.RoleReputer).Inc()
+			suite.Journal.RecordEvent(lib.JournalEvent{
+				Type:        lib.JournalEventWindowMissed,
+				Role:        lib.JournalRoleReputer,
+				TopicId:     reputer.TopicId,
+				BlockHeight: currentBlock,
+				EpochLength: topic.EpochLength,
+				Timestamp:   time.Now(),
+			})
+			waitCancelable(ctx, func() {
+				window.WaitForNextReputerAnticipatedWindowToStart(topic, latestOpenReputerNonce.BlockHeight, currentBlock)
+			})
 		}
 	}
 }
@@ -0,0 +1,17 @@
+package usecase
+
+import "allora_offchain_node/lib"
+
+// UseCaseSuite bundles this node's chain-facing configuration (Node) with
+// the runtime state Spawn creates before fanning out worker/reputer
+// goroutines: the submission-lifecycle journal and the per-topic supervisor
+// registry an admin endpoint can inspect.
+type UseCaseSuite struct {
+	Node lib.NodeConfig
+
+	// Journal is set by Spawn before any worker/reputer goroutine starts.
+	Journal lib.Journal
+
+	// Supervisors is set by Spawn; nil until Spawn runs.
+	Supervisors *SupervisorRegistry
+}
@@ -0,0 +1,199 @@
+package usecase
+
+import (
+	"allora_offchain_node/lib"
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	emissions "github.com/allora-network/allora-chain/x/emissions/types"
+	"github.com/rs/zerolog/log"
+)
+
+// TopicProcessStatus is the state of a single topic's worker or reputer
+// goroutine, as tracked by the SupervisorRegistry.
+type TopicProcessStatus string
+
+const (
+	StatusRegistering TopicProcessStatus = "registering"
+	StatusRunning     TopicProcessStatus = "running"
+	StatusBackoff     TopicProcessStatus = "backoff"
+	StatusStopped     TopicProcessStatus = "stopped"
+)
+
+const (
+	initialBackoff = 2 * time.Second
+	maxBackoff     = 5 * time.Minute
+)
+
+// TopicSupervisorState tracks the restart history of a single topic's
+// worker or reputer process, so an admin endpoint can report on it.
+type TopicSupervisorState struct {
+	mu sync.Mutex
+
+	TopicId      emissions.TopicId
+	Status       TopicProcessStatus
+	FailureCount int
+	LastError    error
+	LastErrorAt  time.Time
+}
+
+func (s *TopicSupervisorState) setStatus(status TopicProcessStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Status = status
+}
+
+func (s *TopicSupervisorState) recordFailure(err error) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.FailureCount++
+	s.LastError = err
+	s.LastErrorAt = time.Now()
+	s.Status = StatusBackoff
+	return s.FailureCount
+}
+
+// recordSuccess resets the backoff counter after a successful commit, so a
+// topic that recovers from a transient RPC failure isn't penalized forever.
+func (s *TopicSupervisorState) recordSuccess() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.FailureCount = 0
+	s.Status = StatusRunning
+}
+
+// SupervisorRegistry is a thread-safe, live view of every topic's worker and
+// reputer process state, keyed by topic id. It is exposed on UseCaseSuite so
+// an admin endpoint can inspect failure counts and last-error timestamps.
+type SupervisorRegistry struct {
+	mu       sync.Mutex
+	workers  map[emissions.TopicId]*TopicSupervisorState
+	reputers map[emissions.TopicId]*TopicSupervisorState
+}
+
+func NewSupervisorRegistry() *SupervisorRegistry {
+	return &SupervisorRegistry{
+		workers:  make(map[emissions.TopicId]*TopicSupervisorState),
+		reputers: make(map[emissions.TopicId]*TopicSupervisorState),
+	}
+}
+
+func (r *SupervisorRegistry) registerWorker(topicId emissions.TopicId) *TopicSupervisorState {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	state := &TopicSupervisorState{TopicId: topicId, Status: StatusRegistering}
+	r.workers[topicId] = state
+	return state
+}
+
+func (r *SupervisorRegistry) registerReputer(topicId emissions.TopicId) *TopicSupervisorState {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	state := &TopicSupervisorState{TopicId: topicId, Status: StatusRegistering}
+	r.reputers[topicId] = state
+	return state
+}
+
+// WorkerStates returns a copy of the worker topic map. Its values are the
+// live supervisor states, not copies, so they keep changing as the
+// supervisors run.
+func (r *SupervisorRegistry) WorkerStates() map[emissions.TopicId]*TopicSupervisorState {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make(map[emissions.TopicId]*TopicSupervisorState, len(r.workers))
+	for topicId, state := range r.workers {
+		out[topicId] = state
+	}
+	return out
+}
+
+// ReputerStates is the reputer-side counterpart of WorkerStates; it too
+// returns a copy of the map whose values are the live states.
+func (r *SupervisorRegistry) ReputerStates() map[emissions.TopicId]*TopicSupervisorState {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make(map[emissions.TopicId]*TopicSupervisorState, len(r.reputers))
+	for topicId, state := range r.reputers {
+		out[topicId] = state
+	}
+	return out
+}
+
+// backoffWithJitter computes an exponential backoff delay for the given
+// failure count, capped at maxBackoff and jittered by up to +/-20% so that
+// many topics failing at once don't all retry in lockstep.
+func backoffWithJitter(failureCount int) time.Duration {
+	delay := initialBackoff * time.Duration(1<<uint(failureCount-1))
+	if delay > maxBackoff || delay <= 0 {
+		delay = maxBackoff
+	}
+	jitter := time.Duration(rand.Int63n(int64(delay) / 5))
+	if rand.Intn(2) == 0 {
+		return delay + jitter
+	}
+	return delay - jitter
+}
+
+// superviseWorkerProcess owns the Registering -> Running -> Backoff -> Stopped
+// state machine for a single topic's worker process. If runWorkerProcess
+// returns an error (rather than exiting cleanly because ctx was cancelled),
+// it is restarted after an exponentially increasing delay.
+func (suite *UseCaseSuite) superviseWorkerProcess(ctx context.Context, worker lib.WorkerConfig, state *TopicSupervisorState) {
+	for {
+		if ctx.Err() != nil {
+			state.setStatus(StatusStopped)
+			return
+		}
+
+		state.setStatus(StatusRunning)
+		err := suite.runWorkerProcess(ctx, worker, state)
+		if err == nil {
+			state.setStatus(StatusStopped)
+			return
+		}
+
+		failureCount := state.recordFailure(err)
+		delay := backoffWithJitter(failureCount)
+		log.Warn().Err(err).Uint64("topicId", worker.TopicId).Int("failureCount", failureCount).Dur("backoff", delay).
+			Msg("Worker process for topic terminated abnormally, restarting after backoff")
+
+		select {
+		case <-ctx.Done():
+			state.setStatus(StatusStopped)
+			return
+		case <-time.After(delay):
+		}
+	}
+}
+
+// superviseReputerProcess is the reputer-side counterpart of
+// superviseWorkerProcess.
+func (suite *UseCaseSuite) superviseReputerProcess(ctx context.Context, reputer lib.ReputerConfig, state *TopicSupervisorState) {
+	for {
+		if ctx.Err() != nil {
+			state.setStatus(StatusStopped)
+			return
+		}
+
+		state.setStatus(StatusRunning)
+		err := suite.runReputerProcess(ctx, reputer, state)
+		if err == nil {
+			state.setStatus(StatusStopped)
+			return
+		}
+
+		failureCount := state.recordFailure(err)
+		delay := backoffWithJitter(failureCount)
+		log.Warn().Err(err).Uint64("topicId", reputer.TopicId).Int("failureCount", failureCount).Dur("backoff", delay).
+			Msg("Reputer process for topic terminated abnormally, restarting after backoff")
+
+		select {
+		case <-ctx.Done():
+			state.setStatus(StatusStopped)
+			return
+		case <-time.After(delay):
+		}
+	}
+}
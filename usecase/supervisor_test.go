@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBackoffWithJitterGrowsAndCaps(t *testing.T) {
+	prev := time.Duration(0)
+	for failureCount := 1; failureCount <= 20; failureCount++ {
+		delay := backoffWithJitter(failureCount)
+		if delay <= 0 {
+			t.Fatalf("backoffWithJitter(%d) = %v, want > 0", failureCount, delay)
+		}
+		if delay > maxBackoff+maxBackoff/5 {
+			t.Fatalf("backoffWithJitter(%d) = %v, want <= maxBackoff plus jitter (%v)", failureCount, delay, maxBackoff)
+		}
+		// Below the cap each step doubles the base delay, which outpaces the
+		// +/-20% jitter, so every delay must exceed the previous one
+		if initialBackoff<<uint(failureCount-1) <= maxBackoff && delay <= prev {
+			t.Fatalf("backoffWithJitter(%d) = %v, want > previous delay %v while below the cap", failureCount, delay, prev)
+		}
+		prev = delay
+	}
+}
+
+func TestBackoffWithJitterStaysNearMaxOnceCapped(t *testing.T) {
+	delay := backoffWithJitter(63) // 1<<62 overflows well past maxBackoff
+	if delay < maxBackoff-maxBackoff/5 || delay > maxBackoff+maxBackoff/5 {
+		t.Fatalf("backoffWithJitter(63) = %v, want within +/-20%% of maxBackoff (%v)", delay, maxBackoff)
+	}
+}
+
+func TestSupervisorRegistryRegisterWorkerRecordsStateByTopic(t *testing.T) {
+	registry := NewSupervisorRegistry()
+	state := registry.registerWorker(5)
+	if state.TopicId != 5 || state.Status != StatusRegistering {
+		t.Fatalf("registerWorker() = %+v, want TopicId=5 Status=registering", state)
+	}
+
+	states := registry.WorkerStates()
+	if len(states) != 1 || states[5] != state {
+		t.Fatalf("WorkerStates() = %+v, want single entry matching registerWorker's return value", states)
+	}
+}
+
+func TestTopicSupervisorStateRecordFailureAndSuccess(t *testing.T) {
+	state := &TopicSupervisorState{TopicId: 1, Status: StatusRunning}
+
+	count := state.recordFailure(errors.New("boom"))
+	if count != 1 || state.Status != StatusBackoff || state.LastError == nil {
+		t.Fatalf("after first recordFailure: count=%d status=%s err=%v, want count=1 status=backoff err!=nil", count, state.Status, state.LastError)
+	}
+
+	count = state.recordFailure(errors.New("boom again"))
+	if count != 2 {
+		t.Fatalf("after second recordFailure: count=%d, want 2", count)
+	}
+
+	state.recordSuccess()
+	if state.FailureCount != 0 || state.Status != StatusRunning {
+		t.Fatalf("after recordSuccess: FailureCount=%d Status=%s, want 0/running", state.FailureCount, state.Status)
+	}
+}